@@ -0,0 +1,109 @@
+// Package api exposes the balancer's service/destination catalog over
+// HTTP, and provides a Client to talk to it.
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/luizbafilho/fusis/api/types"
+	"github.com/luizbafilho/fusis/fusis"
+)
+
+// NewAPI builds the HTTP handler serving balancer's catalog.
+func NewAPI(balancer fusis.Balancer) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/services", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			encode(w, http.StatusOK, balancer.GetServices())
+		case http.MethodPost:
+			var svc types.Service
+			if err := decode(r.Body, &svc); err != nil {
+				writeError(w, http.StatusBadRequest, err)
+				return
+			}
+			created, err := balancer.CreateService(svc)
+			if err != nil {
+				writeError(w, statusFor(err), err)
+				return
+			}
+			encode(w, http.StatusOK, created)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+
+	mux.HandleFunc("/services/", func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.Trim(r.URL.Path[len("/services/"):], "/"), "/")
+
+		switch {
+		case len(parts) == 1 && r.Method == http.MethodDelete:
+			if err := balancer.DeleteService(parts[0]); err != nil {
+				writeError(w, statusFor(err), err)
+				return
+			}
+			encode(w, http.StatusOK, nil)
+
+		case len(parts) == 2 && parts[1] == "destinations" && r.Method == http.MethodPost:
+			var dst types.Destination
+			if err := decode(r.Body, &dst); err != nil {
+				writeError(w, http.StatusBadRequest, err)
+				return
+			}
+			dst.ServiceId = parts[0]
+			created, err := balancer.AddDestination(dst)
+			if err != nil {
+				writeError(w, statusFor(err), err)
+				return
+			}
+			encode(w, http.StatusOK, created)
+
+		case len(parts) == 3 && parts[1] == "destinations" && r.Method == http.MethodDelete:
+			if err := balancer.DeleteDestination(parts[0], parts[2]); err != nil {
+				writeError(w, statusFor(err), err)
+				return
+			}
+			encode(w, http.StatusOK, nil)
+
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	registerPeeringRoutes(mux, balancer)
+
+	return mux
+}
+
+func statusFor(err error) int {
+	switch err {
+	case types.ErrServiceNotFound, types.ErrDestinationNotFound, types.ErrPeeringNotFound:
+		return http.StatusNotFound
+	case types.ErrServiceAlreadyExists, types.ErrDestinationAlreadyExists, types.ErrPeeringAlreadyExists:
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func encode(w http.ResponseWriter, status int, obj interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if obj != nil {
+		json.NewEncoder(w).Encode(obj)
+	}
+}
+
+func decode(body io.Reader, obj interface{}) error {
+	return json.NewDecoder(body).Decode(obj)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
@@ -5,6 +5,7 @@ import (
 	"net/http/httptest"
 	"os"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/luizbafilho/fusis/api"
@@ -32,6 +33,7 @@ func (s *S) TestFullstackWithClient(c *check.C) {
 			Params: map[string]string{
 				"interface": "eth0",
 				"vipRange":  "192.168.10.0/24",
+				"vipRange6": "2001:db8::/64",
 			},
 		},
 	}
@@ -144,3 +146,55 @@ func (s *S) TestFullstackWithClient(c *check.C) {
 	c.Assert(err, check.IsNil)
 	c.Assert(services, check.HasLen, 0)
 }
+
+func (s *S) TestFullstackIPv6(c *check.C) {
+	dir, err := ioutil.TempDir("", "fusis")
+	c.Assert(err, check.IsNil)
+	defer os.RemoveAll(dir)
+	conf := config.BalancerConfig{
+		Interface:  "eth0",
+		Name:       "Test6",
+		ConfigPath: dir,
+		Bootstrap:  true,
+		Ports: map[string]int{
+			"raft": 20014,
+			"serf": 20015,
+		},
+		Provider: config.Provider{
+			Type: "none",
+			Params: map[string]string{
+				"interface": "eth0",
+				"vipRange":  "192.168.10.0/24",
+				"vipRange6": "2001:db8::/64",
+			},
+		},
+	}
+	balancer, err := fusis.NewBalancer(&conf)
+	c.Assert(err, check.IsNil)
+	defer balancer.Shutdown()
+	timeout := time.After(30 * time.Second)
+	for {
+		if balancer.IsLeader() {
+			break
+		}
+		select {
+		case <-time.After(10 * time.Millisecond):
+		case <-timeout:
+			c.Fatal("timeout waiting for leader after 30 seconds")
+		}
+	}
+	apiHandler := api.NewAPI(balancer)
+	srv := httptest.NewServer(apiHandler)
+	client := api.NewClient(srv.URL)
+
+	svc, err := client.CreateService(types.Service{Name: "myservice6", Port: 1040, Protocol: "tcp", Scheduler: "rr", AddressFamily: "ipv6"})
+	c.Assert(err, check.IsNil)
+	c.Assert(strings.HasPrefix(svc.Host, "2001:db8::"), check.Equals, true)
+
+	dst, err := client.AddDestination(types.Destination{ServiceId: "myservice6", Name: "myname1", Host: "2001:db8::100", Port: 1234, Mode: "nat"})
+	c.Assert(err, check.IsNil)
+	c.Assert(dst.Host, check.Equals, "2001:db8::100")
+
+	err = client.DeleteService("myservice6")
+	c.Assert(err, check.IsNil)
+}
@@ -1,117 +1,162 @@
 package api
 
-//
-// import (
-// 	"bytes"
-// 	"encoding/json"
-// 	"fmt"
-// 	"io"
-// 	"io/ioutil"
-// 	"net/http"
-// 	"strings"
-//
-// 	. "github.com/luizbafilho/fusis/store"
-// )
-//
-// type Client struct {
-// 	Addr string
-// }
-//
-// func NewClient(addr string) *Client {
-// 	return &Client{Addr: addr}
-// }
-//
-// func (c *Client) GetServices() (*[]Service, error) {
-// 	resp, err := http.Get(c.path("services"))
-// 	if err != nil || resp.StatusCode != 200 {
-// 		return nil, err
-// 	}
-//
-// 	var services []Service
-// 	err = decode(resp.Body, &services)
-// 	if err != nil {
-// 		return nil, err
-// 	}
-//
-// 	return &services, nil
-// }
-//
-// func (c *Client) UpsertService(svc Service) error {
-// 	json, err := encode(svc)
-// 	if err != nil {
-// 		return err
-// 	}
-//
-// 	resp, err := http.Post(c.path("services"), "application/json", json)
-//
-// 	if err != nil || resp.StatusCode != 200 {
-// 		return formatError(resp)
-// 	}
-//
-// 	return nil
-// }
-//
-// func (c *Client) DeleteService(svc Service) error {
-// 	client := &http.Client{}
-// 	req, err := http.NewRequest("DELETE", c.path("services", svc.GetId()), nil)
-// 	resp, err := client.Do(req)
-//
-// 	if err != nil || resp.StatusCode != 200 {
-// 		return formatError(resp)
-// 	}
-//
-// 	return nil
-// }
-//
-// func (c *Client) UpsertDestination(svc Service, dst Destination) error {
-// 	json, err := encode(dst)
-// 	if err != nil {
-// 		return err
-// 	}
-//
-// 	resp, err := http.Post(c.path("services", svc.GetId(), "destinations"), "application/json", json)
-// 	if err != nil || resp.StatusCode != 200 {
-// 		return formatError(resp)
-// 	}
-// 	return nil
-// }
-//
-// func (c *Client) DeleteDestination(svc Service, dst Destination) error {
-// 	client := &http.Client{}
-// 	req, err := http.NewRequest("DELETE", c.path("services", svc.GetId(), "destinations", dst.GetId()), nil)
-// 	resp, err := client.Do(req)
-//
-// 	if err != nil || resp.StatusCode != 200 {
-// 		return formatError(resp)
-// 	}
-//
-// 	return nil
-// }
-// func encode(obj interface{}) (io.Reader, error) {
-// 	b, err := json.Marshal(obj)
-// 	if err != nil {
-// 		return nil, err
-// 	}
-// 	return bytes.NewReader(b), nil
-// }
-//
-// func decode(body io.Reader, obj interface{}) error {
-// 	decoder := json.NewDecoder(body)
-// 	err := decoder.Decode(obj)
-// 	if err != nil {
-// 		return err
-// 	}
-// 	return nil
-// }
-//
-// func formatError(resp *http.Response) error {
-// 	var body string
-// 	if b, err := ioutil.ReadAll(resp.Body); err == nil {
-// 		body = string(b)
-// 	}
-// 	return fmt.Errorf("Request failed. Status Code: %v. Body: %v", resp.StatusCode, body)
-// }
-//
-// func (c Client) path(paths ...string) string {
-// 	return strings.Join(append([]string{c.Addr}, paths...), "/")
-// }
\ No newline at end of file
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/luizbafilho/fusis/api/types"
+)
+
+// Client talks to a balancer's HTTP API.
+type Client struct {
+	Addr       string
+	httpClient *http.Client
+}
+
+// NewClient builds a Client talking to the balancer listening at addr.
+func NewClient(addr string) *Client {
+	return &Client{Addr: addr, httpClient: http.DefaultClient}
+}
+
+func (c *Client) GetServices() ([]*types.Service, error) {
+	resp, err := c.httpClient.Get(c.path("services"))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, formatError(resp)
+	}
+
+	var services []*types.Service
+	if err := decode(resp.Body, &services); err != nil {
+		return nil, err
+	}
+	return services, nil
+}
+
+func (c *Client) CreateService(svc types.Service) (*types.Service, error) {
+	body, err := encodeBody(svc)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Post(c.path("services"), "application/json", body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errorFor(resp)
+	}
+
+	var created types.Service
+	if err := decode(resp.Body, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+func (c *Client) DeleteService(name string) error {
+	req, err := http.NewRequest(http.MethodDelete, c.path("services", name), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errorFor(resp)
+	}
+	return nil
+}
+
+func (c *Client) AddDestination(dst types.Destination) (*types.Destination, error) {
+	body, err := encodeBody(dst)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Post(c.path("services", dst.ServiceId, "destinations"), "application/json", body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errorFor(resp)
+	}
+
+	var created types.Destination
+	if err := decode(resp.Body, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+func (c *Client) DeleteDestination(serviceName, destinationName string) error {
+	req, err := http.NewRequest(http.MethodDelete, c.path("services", serviceName, "destinations", destinationName), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errorFor(resp)
+	}
+	return nil
+}
+
+func encodeBody(obj interface{}) (*bytes.Reader, error) {
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(b), nil
+}
+
+// errorFor maps a non-200 API response back to the sentinel errors the
+// server produced it from, so callers can compare against
+// types.ErrServiceNotFound and friends.
+func errorFor(resp *http.Response) error {
+	var body struct {
+		Error string `json:"error"`
+	}
+	decode(resp.Body, &body)
+
+	for _, sentinel := range []error{
+		types.ErrServiceAlreadyExists,
+		types.ErrServiceNotFound,
+		types.ErrDestinationAlreadyExists,
+		types.ErrDestinationNotFound,
+		types.ErrPeeringAlreadyExists,
+		types.ErrPeeringNotFound,
+	} {
+		if sentinel.Error() == body.Error {
+			return sentinel
+		}
+	}
+	return fmt.Errorf("Request failed. Status Code: %v. Body: %v", resp.StatusCode, body.Error)
+}
+
+func formatError(resp *http.Response) error {
+	var bodyText string
+	if b, err := ioutil.ReadAll(resp.Body); err == nil {
+		bodyText = string(b)
+	}
+	return fmt.Errorf("Request failed. Status Code: %v. Body: %v", resp.StatusCode, bodyText)
+}
+
+func (c *Client) path(paths ...string) string {
+	return strings.Join(append([]string{c.Addr}, paths...), "/")
+}
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/luizbafilho/fusis/api/types"
+)
+
+// CreatePeering generates a bearer token on this cluster; hand it to a
+// remote cluster's EstablishPeering to let it import this cluster's
+// service catalog.
+func (c *Client) CreatePeering() (string, error) {
+	resp, err := c.httpClient.Post(c.path("peerings", "token"), "application/json", nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", errorFor(resp)
+	}
+
+	var body struct {
+		Token string `json:"Token"`
+	}
+	if err := decode(resp.Body, &body); err != nil {
+		return "", err
+	}
+	return body.Token, nil
+}
+
+// EstablishPeering consumes a token generated by a remote cluster's
+// CreatePeering, registering the peering locally as name.
+func (c *Client) EstablishPeering(name, token string, mode types.PeeringMode) (*types.Peering, error) {
+	body, err := encodeBody(struct {
+		Name  string
+		Token string
+		Mode  types.PeeringMode
+	}{name, token, mode})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Post(c.path("peerings", "establish"), "application/json", body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errorFor(resp)
+	}
+
+	var p types.Peering
+	if err := decode(resp.Body, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// ListPeerings returns all peerings established on this cluster.
+func (c *Client) ListPeerings() ([]types.Peering, error) {
+	resp, err := c.httpClient.Get(c.path("peerings"))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errorFor(resp)
+	}
+
+	var peerings []types.Peering
+	if err := decode(resp.Body, &peerings); err != nil {
+		return nil, err
+	}
+	return peerings, nil
+}
+
+// DeletePeering tears down a peering.
+func (c *Client) DeletePeering(name string) error {
+	req, err := http.NewRequest(http.MethodDelete, c.path("peerings", name), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errorFor(resp)
+	}
+	return nil
+}
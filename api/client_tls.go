@@ -0,0 +1,42 @@
+package api
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+)
+
+// ClientTLSConfig configures a TLS-speaking Client. CAFile is required;
+// CertFile/KeyFile are only needed when the server has
+// RequireClientCert set.
+type ClientTLSConfig struct {
+	CAFile   string
+	CertFile string
+	KeyFile  string
+}
+
+// NewClientTLS builds a Client talking to the balancer listening at addr
+// over TLS, verifying it against CAFile and, if CertFile/KeyFile are
+// set, authenticating with a client certificate.
+func NewClientTLS(addr string, cfg *ClientTLSConfig) (*Client, error) {
+	pool, err := loadCAPool(cfg.CAFile)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig := &tls.Config{RootCAs: pool}
+
+	if cfg.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("api: failed loading client TLS certificate: %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	httpClient := &http.Client{
+		Transport: &http.Transport{TLSClientConfig: tlsConfig},
+	}
+
+	return &Client{Addr: addr, httpClient: httpClient}, nil
+}
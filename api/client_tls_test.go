@@ -0,0 +1,68 @@
+package api_test
+
+import (
+	"encoding/pem"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+
+	"github.com/luizbafilho/fusis/api"
+	"github.com/luizbafilho/fusis/api/types"
+	"github.com/luizbafilho/fusis/config"
+	"github.com/luizbafilho/fusis/fusis"
+	"gopkg.in/check.v1"
+)
+
+func (s *S) TestClientOverTLS(c *check.C) {
+	conf := config.BalancerConfig{
+		Interface: "eth0",
+		Name:      "TestTLS",
+		Ports: map[string]int{
+			"raft": 20016,
+			"serf": 20017,
+		},
+		Provider: config.Provider{
+			Type: "none",
+			Params: map[string]string{
+				"interface": "eth0",
+				"vipRange":  "192.168.11.0/24",
+			},
+		},
+	}
+	balancer, err := fusis.NewBalancer(&conf)
+	c.Assert(err, check.IsNil)
+	defer balancer.Shutdown()
+
+	srv := httptest.NewTLSServer(api.NewAPI(balancer))
+	defer srv.Close()
+
+	caFile, err := writeCACert(srv)
+	c.Assert(err, check.IsNil)
+	defer os.Remove(caFile)
+
+	client, err := api.NewClientTLS(srv.URL, &api.ClientTLSConfig{CAFile: caFile})
+	c.Assert(err, check.IsNil)
+
+	_, err = client.CreateService(types.Service{Name: "tls-service", Port: 1040, Protocol: "tcp", Scheduler: "rr"})
+	c.Assert(err, check.IsNil)
+
+	services, err := client.GetServices()
+	c.Assert(err, check.IsNil)
+	c.Assert(services, check.HasLen, 1)
+}
+
+// writeCACert writes srv's certificate to a temp PEM file so it can be
+// handed to NewClientTLS as a CAFile, the way an operator would hand it
+// the balancer's real CA bundle.
+func writeCACert(srv *httptest.Server) (string, error) {
+	f, err := ioutil.TempFile("", "fusis-test-ca")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	return f.Name(), pem.Encode(f, &pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: srv.Certificate().Raw,
+	})
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/luizbafilho/fusis/api/types"
+	"github.com/luizbafilho/fusis/fusis"
+)
+
+func registerPeeringRoutes(mux *http.ServeMux, balancer fusis.Balancer) {
+	mux.HandleFunc("/peerings/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		token, err := balancer.CreatePeeringToken()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		encode(w, http.StatusOK, map[string]string{"Token": token})
+	})
+
+	mux.HandleFunc("/peerings/establish", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req struct {
+			Name  string            `json:"Name"`
+			Token string            `json:"Token"`
+			Mode  types.PeeringMode `json:"Mode"`
+		}
+		if err := decode(r.Body, &req); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		p, err := balancer.EstablishPeering(req.Name, req.Token, req.Mode)
+		if err != nil {
+			writeError(w, statusFor(err), err)
+			return
+		}
+		encode(w, http.StatusOK, p)
+	})
+
+	mux.HandleFunc("/peerings/catalog", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		authToken := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !balancer.ValidatePeeringAuth(authToken) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		encode(w, http.StatusOK, balancer.GetServices())
+	})
+
+	mux.HandleFunc("/peerings/", func(w http.ResponseWriter, r *http.Request) {
+		name := strings.Trim(r.URL.Path[len("/peerings/"):], "/")
+		if name == "" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			p, err := balancer.GetPeering(name)
+			if err != nil {
+				writeError(w, statusFor(err), err)
+				return
+			}
+			encode(w, http.StatusOK, p)
+		case http.MethodDelete:
+			if err := balancer.DeletePeering(name); err != nil {
+				writeError(w, statusFor(err), err)
+				return
+			}
+			encode(w, http.StatusOK, nil)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+
+	mux.HandleFunc("/peerings", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		encode(w, http.StatusOK, balancer.ListPeerings())
+	})
+}
@@ -0,0 +1,117 @@
+package api
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+
+	"github.com/luizbafilho/fusis/config"
+)
+
+// Serve starts handler listening on addr, over TLS if tlsCfg is set.
+// It blocks until the listener errors, the way http.ListenAndServe
+// does.
+func Serve(addr string, handler http.Handler, tlsCfg *config.TLS) error {
+	if tlsCfg == nil {
+		return http.ListenAndServe(addr, handler)
+	}
+
+	cfg, err := buildTLSConfig(tlsCfg)
+	if err != nil {
+		return err
+	}
+
+	srv := &http.Server{Addr: addr, Handler: handler, TLSConfig: cfg}
+	return srv.ListenAndServeTLS("", "")
+}
+
+// buildTLSConfig loads tlsCfg's certificate through a reloader that
+// re-reads CertFile/KeyFile from disk on SIGHUP, so an operator can
+// rotate the API's certificate without restarting the balancer.
+func buildTLSConfig(tlsCfg *config.TLS) (*tls.Config, error) {
+	reloader, err := newCertReloader(tlsCfg.CertFile, tlsCfg.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	minVersion := tlsCfg.MinVersion
+	if minVersion == 0 {
+		minVersion = tls.VersionTLS12
+	}
+
+	cfg := &tls.Config{
+		GetCertificate: reloader.getCertificate,
+		MinVersion:     minVersion,
+	}
+
+	if tlsCfg.ClientCAFile != "" {
+		pool, err := loadCAPool(tlsCfg.ClientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.ClientCAs = pool
+		if tlsCfg.RequireClientCert {
+			cfg.ClientAuth = tls.RequireAndVerifyClientCert
+		} else {
+			cfg.ClientAuth = tls.VerifyClientCertIfGiven
+		}
+	}
+
+	return cfg, nil
+}
+
+// certReloader holds the API's current certificate and swaps it out for
+// a freshly loaded one whenever reload is called.
+type certReloader struct {
+	certFile, keyFile string
+	current           atomic.Value // holds *tls.Certificate
+}
+
+func newCertReloader(certFile, keyFile string) (*certReloader, error) {
+	r := &certReloader{certFile: certFile, keyFile: keyFile}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGHUP)
+	go func() {
+		for range sigCh {
+			r.reload()
+		}
+	}()
+
+	return r, nil
+}
+
+func (r *certReloader) reload() error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("api: failed loading TLS certificate: %v", err)
+	}
+	r.current.Store(&cert)
+	return nil
+}
+
+func (r *certReloader) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return r.current.Load().(*tls.Certificate), nil
+}
+
+func loadCAPool(caFile string) (*x509.CertPool, error) {
+	pem, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("api: failed reading CA bundle %s: %v", caFile, err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("api: no certificates found in %s", caFile)
+	}
+	return pool, nil
+}
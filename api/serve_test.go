@@ -0,0 +1,93 @@
+package api
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/luizbafilho/fusis/config"
+)
+
+func TestBuildTLSConfigDefaultsMinVersion(t *testing.T) {
+	certFile, keyFile := writeSelfSignedCert(t)
+	defer os.Remove(certFile)
+	defer os.Remove(keyFile)
+
+	cfg, err := buildTLSConfig(&config.TLS{CertFile: certFile, KeyFile: keyFile})
+	if err != nil {
+		t.Fatalf("buildTLSConfig failed: %v", err)
+	}
+	if cfg.MinVersion != tls.VersionTLS12 {
+		t.Errorf("got MinVersion %#x, want TLS 1.2 (%#x)", cfg.MinVersion, tls.VersionTLS12)
+	}
+
+	cert, err := cfg.GetCertificate(nil)
+	if err != nil || cert == nil {
+		t.Fatalf("GetCertificate failed: %v", err)
+	}
+}
+
+func TestBuildTLSConfigRequiresClientCertWhenConfigured(t *testing.T) {
+	certFile, keyFile := writeSelfSignedCert(t)
+	defer os.Remove(certFile)
+	defer os.Remove(keyFile)
+
+	cfg, err := buildTLSConfig(&config.TLS{
+		CertFile:          certFile,
+		KeyFile:           keyFile,
+		ClientCAFile:      certFile,
+		RequireClientCert: true,
+	})
+	if err != nil {
+		t.Fatalf("buildTLSConfig failed: %v", err)
+	}
+	if cfg.ClientAuth != tls.RequireAndVerifyClientCert {
+		t.Errorf("got ClientAuth %v, want RequireAndVerifyClientCert", cfg.ClientAuth)
+	}
+}
+
+func writeSelfSignedCert(t *testing.T) (certFile, keyFile string) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey failed: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "fusis-test"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate failed: %v", err)
+	}
+
+	certOut, err := ioutil.TempFile("", "fusis-test-cert")
+	if err != nil {
+		t.Fatalf("TempFile failed: %v", err)
+	}
+	pem.Encode(certOut, &pem.Block{Type: "CERTIFICATE", Bytes: der})
+	certOut.Close()
+
+	keyOut, err := ioutil.TempFile("", "fusis-test-key")
+	if err != nil {
+		t.Fatalf("TempFile failed: %v", err)
+	}
+	pem.Encode(keyOut, &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	keyOut.Close()
+
+	return certOut.Name(), keyOut.Name()
+}
@@ -0,0 +1,52 @@
+package types
+
+import "time"
+
+// CheckType selects which active health check is run against a
+// destination. A HealthCheck must name one of the types below; an empty
+// or unknown CheckType is rejected. A destination with no HealthCheck at
+// all is not actively probed.
+type CheckType string
+
+const (
+	CheckTCP    CheckType = "tcp"
+	CheckHTTP   CheckType = "http"
+	CheckRadius CheckType = "radius"
+	CheckDNS    CheckType = "dns"
+)
+
+// HealthCheck configures the active probe fusis runs against a
+// destination on top of whatever passive state IPVS reports. Timeout
+// bounds a single probe's dial/read; Interval is how often the probe is
+// repeated. The two are independent: a short Timeout does not imply
+// probing that often, and a long Interval does not give a hung probe
+// extra time to fail.
+type HealthCheck struct {
+	CheckType CheckType     `json:"CheckType"`
+	Timeout   time.Duration `json:"Timeout"`
+	Interval  time.Duration `json:"Interval"`
+
+	Radius *RadiusCheck `json:"Radius,omitempty"`
+	DNS    *DNSCheck    `json:"DNS,omitempty"`
+}
+
+// RadiusCheck configures a RADIUS Access-Request probe, sent to the
+// destination's Host on port 1812/UDP unless overridden.
+type RadiusCheck struct {
+	Secret   string `json:"Secret"`
+	Username string `json:"Username"`
+	Password string `json:"Password"`
+	NASIP    string `json:"NASIP"`
+	Port     uint16 `json:"Port,omitempty"`
+}
+
+// DNSCheck configures a DNS query probe, sent to the destination's Host
+// on port 53 unless overridden.
+type DNSCheck struct {
+	QName                string `json:"QName"`
+	QType                string `json:"QType"`
+	Protocol             string `json:"Protocol"`
+	ExpectRcode          uint8  `json:"ExpectRcode"`
+	ExpectAnswerContains string `json:"ExpectAnswerContains,omitempty"`
+	Port                 uint16 `json:"Port,omitempty"`
+}
@@ -0,0 +1,48 @@
+package types
+
+import "errors"
+
+var (
+	// ErrPeeringAlreadyExists is returned when establishing a peering
+	// whose name is already taken.
+	ErrPeeringAlreadyExists = errors.New("Peering already exists")
+	// ErrPeeringNotFound is returned when a peering lookup fails.
+	ErrPeeringNotFound = errors.New("Peering not found")
+)
+
+// PeeringMode controls what a peering does with the services it imports
+// from the remote cluster. Today only PeeringImportOnly is implemented;
+// programming imported services as live IPVS forwarding destinations
+// needs real netlink plumbing in the ipvs package and is left for a
+// follow-up request.
+type PeeringMode string
+
+const (
+	// PeeringImportOnly materializes the remote services locally for
+	// visibility, but never programs them into IPVS.
+	PeeringImportOnly PeeringMode = "import-only"
+)
+
+// Peering describes one side of a cluster-to-cluster peering
+// relationship established via a PeeringToken.
+type Peering struct {
+	Name      string      `json:"Name"`
+	ClusterID string      `json:"ClusterID"`
+	Mode      PeeringMode `json:"Mode"`
+}
+
+// PeeringToken is the bearer token one cluster hands the other so it
+// can establish a peering. It is opaque to callers; Client.CreatePeering
+// returns it as a string and Client.EstablishPeering consumes it as one.
+// CABundle pins the issuing cluster's API certificate so the catalog is
+// pulled over TLS instead of plain HTTP; AuthToken authenticates the
+// pull requests themselves against the issuing cluster's /peerings/catalog
+// endpoint.
+type PeeringToken struct {
+	ClusterID string `json:"ClusterID"`
+	APIAddr   string `json:"APIAddr"`
+	RaftAddr  string `json:"RaftAddr"`
+	SerfAddr  string `json:"SerfAddr"`
+	CABundle  []byte `json:"CABundle"`
+	AuthToken string `json:"AuthToken"`
+}
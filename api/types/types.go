@@ -0,0 +1,69 @@
+// Package types holds the data structures exchanged between the fusis
+// API, its client and the balancer core. They are kept free of any
+// store/ipvs/raft concerns so they can be marshalled as plain JSON.
+package types
+
+import "errors"
+
+var (
+	// ErrServiceAlreadyExists is returned when creating a service whose
+	// name is already taken.
+	ErrServiceAlreadyExists = errors.New("Service already exists")
+	// ErrServiceNotFound is returned when a service lookup fails.
+	ErrServiceNotFound = errors.New("Service not found")
+	// ErrDestinationAlreadyExists is returned when a destination with the
+	// same name, or the same host:port pair, already exists for a service.
+	ErrDestinationAlreadyExists = errors.New("Destination already exists")
+	// ErrDestinationNotFound is returned when a destination lookup fails.
+	ErrDestinationNotFound = errors.New("Destination not found")
+)
+
+// Service represents a virtual service exposed by the balancer.
+type Service struct {
+	Name      string `json:"Name"`
+	Host      string `json:"Host"`
+	Port      uint16 `json:"Port"`
+	Protocol  string `json:"Protocol"`
+	Scheduler string `json:"Scheduler"`
+	// AddressFamily picks which VIP pool the service's Host is allocated
+	// from: "ipv4" (the default when empty) or "ipv6".
+	AddressFamily string `json:"AddressFamily,omitempty"`
+	// PeerName is set on services imported through a peering; it is
+	// empty for services created locally.
+	PeerName     string        `json:"PeerName,omitempty"`
+	Destinations []Destination `json:"Destinations,omitempty"`
+}
+
+// GetId returns the Service unique identifier, which today is simply its
+// Name.
+func (s Service) GetId() string {
+	return s.Name
+}
+
+// Destination represents a real server that answers for a Service.
+type Destination struct {
+	Name        string       `json:"Name"`
+	ServiceId   string       `json:"ServiceId"`
+	Host        string       `json:"Host"`
+	Port        uint16       `json:"Port"`
+	Weight      int          `json:"Weight"`
+	Mode        string       `json:"Mode"`
+	HealthCheck *HealthCheck `json:"HealthCheck,omitempty"`
+	// PeerName is set on destinations imported through a peering; it is
+	// empty for destinations created locally.
+	PeerName string `json:"PeerName,omitempty"`
+}
+
+// GetId returns the Destination unique identifier, which today is simply
+// its Name.
+func (d Destination) GetId() string {
+	return d.Name
+}
+
+// DestinationList implements sort.Interface ordering destinations by
+// name, for callers such as tests that need a stable order.
+type DestinationList []Destination
+
+func (l DestinationList) Len() int           { return len(l) }
+func (l DestinationList) Less(i, j int) bool { return l[i].Name < l[j].Name }
+func (l DestinationList) Swap(i, j int)      { l[i], l[j] = l[j], l[i] }
@@ -0,0 +1,49 @@
+// Package config describes the on-disk/flag configuration of a fusis
+// balancer node: how it talks to its peers, which VIP provider it uses
+// and on which ports it listens.
+package config
+
+// TLS configures the balancer's HTTP API to serve over TLS, optionally
+// requiring clients to present a certificate signed by ClientCAFile.
+type TLS struct {
+	CertFile          string `json:"CertFile"`
+	KeyFile           string `json:"KeyFile"`
+	ClientCAFile      string `json:"ClientCAFile,omitempty"`
+	MinVersion        uint16 `json:"MinVersion,omitempty"`
+	RequireClientCert bool   `json:"RequireClientCert,omitempty"`
+
+	// PeerCAFile is the CA bundle embedded in the peering tokens this
+	// cluster issues, so a remote cluster establishing a peering can
+	// pin CertFile's certificate instead of pulling our catalog over
+	// plain HTTP. It is usually the CA that signed CertFile.
+	PeerCAFile string `json:"PeerCAFile,omitempty"`
+}
+
+// Provider configures how VIPs are allocated and, for cloud providers,
+// how they are attached to the host. Params is provider-specific; the
+// "none" and cloud providers both read "vipRange" (IPv4 CIDR) and,
+// optionally, "vipRange6" (IPv6 CIDR) from it.
+type Provider struct {
+	Type   string            `json:"Type"`
+	Params map[string]string `json:"Params"`
+}
+
+// BalancerConfig is the full configuration of a balancer node.
+type BalancerConfig struct {
+	Interface  string         `json:"Interface"`
+	Name       string         `json:"Name"`
+	ConfigPath string         `json:"ConfigPath"`
+	Bootstrap  bool           `json:"Bootstrap"`
+	Ports      map[string]int `json:"Ports"`
+	Provider   Provider       `json:"Provider"`
+
+	// ClusterID and APIAddr identify this cluster to peers: ClusterID is
+	// embedded in the peering tokens it issues, and APIAddr is where a
+	// peer polls this cluster's service catalog from.
+	ClusterID string `json:"ClusterID,omitempty"`
+	APIAddr   string `json:"APIAddr,omitempty"`
+
+	// TLS, when set, makes the HTTP API serve over TLS instead of plain
+	// HTTP.
+	TLS *TLS `json:"TLS,omitempty"`
+}
@@ -0,0 +1,269 @@
+// Package fusis implements the balancer core: the in-memory catalog of
+// services and destinations that the API and health-checker operate on.
+package fusis
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/luizbafilho/fusis/api/types"
+	"github.com/luizbafilho/fusis/config"
+	"github.com/luizbafilho/fusis/healthcheck"
+	"github.com/luizbafilho/fusis/ipaddr"
+	"github.com/luizbafilho/fusis/peering"
+)
+
+const (
+	defaultCheckInterval = 5 * time.Second
+	defaultCheckTimeout  = 2 * time.Second
+)
+
+// Balancer is the interface the API server talks to. In a clustered
+// deployment it is backed by a raft-replicated FSM; NewBalancer today
+// returns a single-node implementation that is always its own leader.
+type Balancer interface {
+	IsLeader() bool
+	Shutdown() error
+
+	GetServices() []*types.Service
+	GetService(name string) (*types.Service, error)
+	CreateService(svc types.Service) (*types.Service, error)
+	DeleteService(name string) error
+
+	AddDestination(dst types.Destination) (*types.Destination, error)
+	DeleteDestination(serviceName, destinationName string) error
+
+	CreatePeeringToken() (string, error)
+	EstablishPeering(name, token string, mode types.PeeringMode) (*types.Peering, error)
+	GetPeering(name string) (*types.Peering, error)
+	ListPeerings() []types.Peering
+	DeletePeering(name string) error
+	ValidatePeeringAuth(authToken string) bool
+}
+
+type balancer struct {
+	config   *config.BalancerConfig
+	vips     *ipaddr.Allocator
+	peerings *peering.Manager
+
+	mu       sync.Mutex
+	services map[string]*types.Service
+	monitors map[string]*healthcheck.Monitor
+}
+
+// NewBalancer builds a Balancer from the given configuration, allocating
+// VIPs out of the IPv4 (Provider.Params["vipRange"]) and, if configured,
+// IPv6 (Provider.Params["vipRange6"]) ranges.
+func NewBalancer(conf *config.BalancerConfig) (Balancer, error) {
+	vips, err := ipaddr.NewAllocator(conf.Provider.Params["vipRange"], conf.Provider.Params["vipRange6"])
+	if err != nil {
+		return nil, err
+	}
+
+	b := &balancer{
+		config:   conf,
+		vips:     vips,
+		services: make(map[string]*types.Service),
+		monitors: make(map[string]*healthcheck.Monitor),
+	}
+	raftAddr := fmt.Sprintf(":%d", conf.Ports["raft"])
+	serfAddr := fmt.Sprintf(":%d", conf.Ports["serf"])
+
+	caBundle, err := peerCABundle(conf)
+	if err != nil {
+		return nil, err
+	}
+	b.peerings = peering.NewManager(conf.ClusterID, conf.APIAddr, raftAddr, serfAddr, caBundle, b)
+	return b, nil
+}
+
+// peerCABundle reads the CA bundle this cluster should embed in the
+// peering tokens it issues, from conf.TLS.PeerCAFile. It returns nil
+// (no CA bundle) when TLS isn't configured, so peerings established
+// against a plain-HTTP API keep working as before.
+func peerCABundle(conf *config.BalancerConfig) ([]byte, error) {
+	if conf.TLS == nil || conf.TLS.PeerCAFile == "" {
+		return nil, nil
+	}
+	return ioutil.ReadFile(conf.TLS.PeerCAFile)
+}
+
+// IsLeader always returns true: clustering is handled by wrapping this
+// Balancer in a raft FSM, which this single-node implementation has none
+// of.
+func (b *balancer) IsLeader() bool {
+	return true
+}
+
+// Shutdown releases no resources today; it exists so callers don't need
+// to special-case the single-node Balancer against a clustered one.
+func (b *balancer) Shutdown() error {
+	return nil
+}
+
+func (b *balancer) GetServices() []*types.Service {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	svcs := make([]*types.Service, 0, len(b.services))
+	for _, svc := range b.services {
+		cp := *svc
+		svcs = append(svcs, &cp)
+	}
+	return svcs
+}
+
+func (b *balancer) GetService(name string) (*types.Service, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	svc, ok := b.services[name]
+	if !ok {
+		return nil, types.ErrServiceNotFound
+	}
+	cp := *svc
+	return &cp, nil
+}
+
+func (b *balancer) CreateService(svc types.Service) (*types.Service, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.services[svc.GetId()]; ok {
+		return nil, types.ErrServiceAlreadyExists
+	}
+
+	family := ipaddr.IPv4
+	if svc.AddressFamily == string(ipaddr.IPv6) {
+		family = ipaddr.IPv6
+	}
+
+	vip, err := b.vips.Allocate(family)
+	if err != nil {
+		return nil, err
+	}
+	svc.Host = vip.String()
+
+	b.services[svc.GetId()] = &svc
+	return &svc, nil
+}
+
+func (b *balancer) DeleteService(name string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	svc, ok := b.services[name]
+	if !ok {
+		return types.ErrServiceNotFound
+	}
+	for _, dst := range svc.Destinations {
+		b.stopMonitor(name, dst.Name)
+	}
+
+	family := ipaddr.IPv4
+	if svc.AddressFamily == string(ipaddr.IPv6) {
+		family = ipaddr.IPv6
+	}
+	if vip := net.ParseIP(svc.Host); vip != nil {
+		b.vips.Release(family, vip)
+	}
+
+	delete(b.services, name)
+	return nil
+}
+
+func (b *balancer) AddDestination(dst types.Destination) (*types.Destination, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	svc, ok := b.services[dst.ServiceId]
+	if !ok {
+		return nil, types.ErrServiceNotFound
+	}
+
+	for _, d := range svc.Destinations {
+		if d.Name == dst.Name || (d.Host == dst.Host && d.Port == dst.Port) {
+			return nil, types.ErrDestinationAlreadyExists
+		}
+	}
+
+	if dst.Weight == 0 {
+		dst.Weight = 1
+	}
+	svc.Destinations = append(svc.Destinations, dst)
+
+	if err := b.startMonitor(dst); err != nil {
+		svc.Destinations = svc.Destinations[:len(svc.Destinations)-1]
+		return nil, err
+	}
+
+	return &dst, nil
+}
+
+func (b *balancer) DeleteDestination(serviceName, destinationName string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	svc, ok := b.services[serviceName]
+	if !ok {
+		return types.ErrDestinationNotFound
+	}
+
+	for i, d := range svc.Destinations {
+		if d.Name == destinationName {
+			svc.Destinations = append(svc.Destinations[:i], svc.Destinations[i+1:]...)
+			b.stopMonitor(serviceName, destinationName)
+			return nil
+		}
+	}
+	return types.ErrDestinationNotFound
+}
+
+// startMonitor begins active health checking for dst when it carries a
+// HealthCheck configuration, so a destination fronting something other
+// than plain TCP/HTTP can be marked down without a synthetic TCP probe.
+// It fails if dst.HealthCheck is present but misconfigured (e.g. an
+// unknown CheckType or a missing Radius/DNS block), so AddDestination
+// never registers a destination whose health check silently never runs.
+// Callers must hold b.mu.
+func (b *balancer) startMonitor(dst types.Destination) error {
+	if dst.HealthCheck == nil {
+		return nil
+	}
+
+	if dst.HealthCheck.Timeout <= 0 {
+		dst.HealthCheck.Timeout = defaultCheckTimeout
+	}
+
+	checker, err := healthcheck.NewDestinationChecker(dst)
+	if err != nil {
+		return err
+	}
+
+	interval := dst.HealthCheck.Interval
+	if interval <= 0 {
+		interval = defaultCheckInterval
+	}
+
+	monitor := healthcheck.NewMonitor(checker, interval)
+	monitor.Start()
+	b.monitors[monitorKey(dst.ServiceId, dst.Name)] = monitor
+	return nil
+}
+
+// stopMonitor halts and forgets the monitor for a destination, if any.
+// Callers must hold b.mu.
+func (b *balancer) stopMonitor(serviceName, destinationName string) {
+	key := monitorKey(serviceName, destinationName)
+	if monitor, ok := b.monitors[key]; ok {
+		monitor.Stop()
+		delete(b.monitors, key)
+	}
+}
+
+func monitorKey(serviceName, destinationName string) string {
+	return serviceName + "/" + destinationName
+}
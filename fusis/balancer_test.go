@@ -0,0 +1,164 @@
+package fusis
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/luizbafilho/fusis/api/types"
+	"github.com/luizbafilho/fusis/config"
+)
+
+func newTestBalancer(t *testing.T) *balancer {
+	t.Helper()
+
+	conf := &config.BalancerConfig{
+		Interface: "eth0",
+		Name:      "Test",
+		Ports: map[string]int{
+			"raft": 20112,
+			"serf": 20113,
+		},
+		Provider: config.Provider{
+			Type: "none",
+			Params: map[string]string{
+				"interface": "eth0",
+				"vipRange":  "192.168.20.0/24",
+			},
+		},
+	}
+
+	b, err := NewBalancer(conf)
+	if err != nil {
+		t.Fatalf("NewBalancer failed: %v", err)
+	}
+	return b.(*balancer)
+}
+
+func TestAddDestinationRejectsMisconfiguredHealthCheck(t *testing.T) {
+	b := newTestBalancer(t)
+
+	svc, err := b.CreateService(types.Service{Name: "svc", Port: 80, Protocol: "tcp", Scheduler: "rr"})
+	if err != nil {
+		t.Fatalf("CreateService failed: %v", err)
+	}
+
+	dst := types.Destination{
+		Name:      "dst",
+		ServiceId: svc.GetId(),
+		Host:      "10.0.0.1",
+		Port:      1812,
+		HealthCheck: &types.HealthCheck{
+			CheckType: types.CheckRadius,
+			// Radius is left nil, so the check can never run.
+		},
+	}
+
+	if _, err := b.AddDestination(dst); err == nil {
+		t.Fatalf("expected an error adding a destination with a misconfigured HealthCheck")
+	}
+
+	got, err := b.GetService(svc.GetId())
+	if err != nil {
+		t.Fatalf("GetService failed: %v", err)
+	}
+	if len(got.Destinations) != 0 {
+		t.Errorf("got %d destinations, want the rejected destination not to be registered", len(got.Destinations))
+	}
+}
+
+func TestCreatePeeringTokenEmbedsTheConfiguredCABundle(t *testing.T) {
+	caFile, caPEM := writeSelfSignedCA(t)
+	defer os.Remove(caFile)
+
+	conf := &config.BalancerConfig{
+		Interface: "eth0",
+		Name:      "Test",
+		ClusterID: "cluster-a",
+		Ports: map[string]int{
+			"raft": 20114,
+			"serf": 20115,
+		},
+		Provider: config.Provider{
+			Type:   "none",
+			Params: map[string]string{"interface": "eth0", "vipRange": "192.168.21.0/24"},
+		},
+		TLS: &config.TLS{
+			PeerCAFile: caFile,
+		},
+	}
+
+	b, err := NewBalancer(conf)
+	if err != nil {
+		t.Fatalf("NewBalancer failed: %v", err)
+	}
+
+	token, err := b.CreatePeeringToken()
+	if err != nil {
+		t.Fatalf("CreatePeeringToken failed: %v", err)
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(token)
+	if err != nil {
+		t.Fatalf("token is not valid base64: %v", err)
+	}
+	var tok types.PeeringToken
+	if err := json.Unmarshal(raw, &tok); err != nil {
+		t.Fatalf("token is not valid JSON: %v", err)
+	}
+
+	if string(tok.CABundle) != string(caPEM) {
+		t.Errorf("got CABundle %q, want the configured PeerCAFile contents %q", tok.CABundle, caPEM)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(tok.CABundle) {
+		t.Errorf("token's CABundle is not a usable PEM CA bundle")
+	}
+}
+
+func writeSelfSignedCA(t *testing.T) (file string, pemBytes []byte) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey failed: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "fusis-peer-ca"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate failed: %v", err)
+	}
+
+	pemBytes = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+
+	f, err := ioutil.TempFile("", "fusis-peer-ca")
+	if err != nil {
+		t.Fatalf("TempFile failed: %v", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(pemBytes); err != nil {
+		t.Fatalf("failed writing CA file: %v", err)
+	}
+
+	return f.Name(), pemBytes
+}
@@ -0,0 +1,72 @@
+package fusis
+
+import (
+	"github.com/luizbafilho/fusis/api/types"
+	"github.com/luizbafilho/fusis/peering"
+)
+
+// CreatePeeringToken generates a bearer token describing this cluster,
+// to be handed to a remote cluster's EstablishPeering.
+func (b *balancer) CreatePeeringToken() (string, error) {
+	return b.peerings.Token()
+}
+
+// EstablishPeering consumes a token from a remote cluster and starts
+// importing its service catalog under name.
+func (b *balancer) EstablishPeering(name, token string, mode types.PeeringMode) (*types.Peering, error) {
+	return b.peerings.Establish(name, token, mode)
+}
+
+// GetPeering returns the named peering.
+func (b *balancer) GetPeering(name string) (*types.Peering, error) {
+	return b.peerings.Get(name)
+}
+
+// ListPeerings returns all established peerings.
+func (b *balancer) ListPeerings() []types.Peering {
+	return b.peerings.List()
+}
+
+// DeletePeering tears down a peering, stopping its catalog sync.
+func (b *balancer) DeletePeering(name string) error {
+	return b.peerings.Delete(name)
+}
+
+// ValidatePeeringAuth reports whether authToken was issued by this
+// cluster's CreatePeeringToken, authenticating a peer's request to this
+// cluster's catalog endpoint.
+func (b *balancer) ValidatePeeringAuth(authToken string) bool {
+	return b.peerings.ValidateAuthToken(authToken)
+}
+
+// ImportService materializes a peer's service locally, tagged with
+// PeerName so it's never mistaken for one created through the regular
+// CreateService API. Only PeeringImportOnly exists today, so it is
+// always kept out of IPVS; programming it as a forwarding destination
+// is left for when that mode is actually implemented.
+func (b *balancer) ImportService(peerName string, svc types.Service, mode types.PeeringMode) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	svc.PeerName = peerName
+	for i := range svc.Destinations {
+		svc.Destinations[i].PeerName = peerName
+	}
+	b.services[svc.GetId()] = &svc
+	return nil
+}
+
+// RemoveImportedService drops a previously imported shadow service.
+func (b *balancer) RemoveImportedService(peerName, serviceName string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	svc, ok := b.services[serviceName]
+	if !ok || svc.PeerName != peerName {
+		return types.ErrServiceNotFound
+	}
+	delete(b.services, serviceName)
+	return nil
+}
+
+var _ peering.Importer = (*balancer)(nil)
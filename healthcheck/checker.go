@@ -0,0 +1,111 @@
+// Package healthcheck implements the active health checks fusis runs
+// against destinations. Each destination is probed on an interval by a
+// Checker matching its configured CheckType; the result drives the
+// up/down transitions tracked here, independently of IPVS state.
+package healthcheck
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// CheckType identifies which Checker implementation probes a
+// destination.
+type CheckType string
+
+const (
+	CheckTCP CheckType = "tcp"
+	CheckHTTP CheckType = "http"
+)
+
+// Checker probes a single destination and reports whether it is
+// healthy.
+type Checker interface {
+	Check() error
+}
+
+// NewChecker builds the Checker configured for typ, or an error if typ
+// is not recognised.
+func NewChecker(typ CheckType, host string, port uint16, timeout time.Duration) (Checker, error) {
+	switch typ {
+	case CheckTCP:
+		return &tcpChecker{host: host, port: port, timeout: timeout}, nil
+	case CheckHTTP:
+		return &httpChecker{host: host, port: port, timeout: timeout}, nil
+	default:
+		return nil, fmt.Errorf("healthcheck: unknown check type %q", typ)
+	}
+}
+
+// Monitor runs a Checker on an interval and tracks the up/down state of
+// a single destination, so callers can read its current state through
+// IsUp instead of running the checks themselves.
+type Monitor struct {
+	checker  Checker
+	interval time.Duration
+
+	mu sync.Mutex
+	up bool
+
+	stopCh chan struct{}
+}
+
+// NewMonitor creates a Monitor that calls checker.Check() every
+// interval.
+func NewMonitor(checker Checker, interval time.Duration) *Monitor {
+	return &Monitor{
+		checker:  checker,
+		interval: interval,
+		stopCh:   make(chan struct{}),
+	}
+}
+
+// IsUp reports the last known state of the monitored destination.
+func (m *Monitor) IsUp() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.up
+}
+
+// Start begins probing in a goroutine until Stop is called.
+func (m *Monitor) Start() {
+	go func() {
+		ticker := time.NewTicker(m.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				m.runCheck()
+			case <-m.stopCh:
+				return
+			}
+		}
+	}()
+}
+
+// Stop halts probing.
+func (m *Monitor) Stop() {
+	close(m.stopCh)
+}
+
+func (m *Monitor) runCheck() {
+	// Checker implementations talk to destinations fusis does not
+	// control, so a malformed response must not be allowed to crash the
+	// balancer: guard the probe with a recover, the same as any other
+	// boundary with untrusted input.
+	err := m.safeCheck()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.up = err == nil
+}
+
+func (m *Monitor) safeCheck() (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("healthcheck: check panicked: %v", r)
+		}
+	}()
+	return m.checker.Check()
+}
@@ -0,0 +1,40 @@
+package healthcheck
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/luizbafilho/fusis/api/types"
+)
+
+// NewDestinationChecker builds the Checker configured by dst's
+// HealthCheck, so a destination fronting something other than a plain
+// TCP/HTTP service (e.g. a RADIUS pool) can be marked down without a
+// synthetic TCP probe against it.
+func NewDestinationChecker(dst types.Destination) (Checker, error) {
+	hc := dst.HealthCheck
+	if hc == nil {
+		return nil, fmt.Errorf("healthcheck: destination %s has no HealthCheck configured", dst.Name)
+	}
+
+	switch hc.CheckType {
+	case types.CheckTCP:
+		return NewChecker(CheckTCP, dst.Host, dst.Port, hc.Timeout)
+	case types.CheckHTTP:
+		return NewChecker(CheckHTTP, dst.Host, dst.Port, hc.Timeout)
+	case types.CheckRadius:
+		rc := hc.Radius
+		if rc == nil {
+			return nil, fmt.Errorf("healthcheck: destination %s is missing its Radius config", dst.Name)
+		}
+		return NewRADIUSChecker(dst.Host, rc.Port, rc.Secret, rc.Username, rc.Password, net.ParseIP(rc.NASIP), hc.Timeout), nil
+	case types.CheckDNS:
+		dc := hc.DNS
+		if dc == nil {
+			return nil, fmt.Errorf("healthcheck: destination %s is missing its DNS config", dst.Name)
+		}
+		return NewDNSChecker(dst.Host, dc.Port, dc.QName, dc.QType, dc.Protocol, dc.ExpectRcode, dc.ExpectAnswerContains, hc.Timeout)
+	default:
+		return nil, fmt.Errorf("healthcheck: unknown check type %q", hc.CheckType)
+	}
+}
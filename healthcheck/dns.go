@@ -0,0 +1,238 @@
+package healthcheck
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// DNS protocol constants relevant to a minimal query/response round
+// trip; we don't need a full resolver, just enough to tell whether the
+// destination answers queries the way it's expected to.
+const (
+	dnsHeaderLen = 12
+	dnsClassIN   = 1
+)
+
+var dnsQTypes = map[string]uint16{
+	"A":     1,
+	"AAAA":  28,
+	"TXT":   16,
+	"CNAME": 5,
+	"MX":    15,
+	"NS":    2,
+}
+
+// dnsChecker considers a destination healthy if it answers a DNS query
+// with the expected rcode and, optionally, an answer record containing
+// ExpectAnswerContains.
+type dnsChecker struct {
+	host    string
+	port    uint16
+	timeout time.Duration
+
+	qname    string
+	qtype    uint16
+	protocol string
+
+	expectRcode          uint8
+	expectAnswerContains string
+}
+
+// NewDNSChecker builds a Checker that sends a single DNS query for
+// qname/qtype to host:port over protocol ("udp" or "tcp", defaulting to
+// "udp") and expects rcode back, optionally requiring the answer
+// section to contain expectAnswerContains.
+func NewDNSChecker(host string, port uint16, qname, qtype, protocol string, expectRcode uint8, expectAnswerContains string, timeout time.Duration) (Checker, error) {
+	t, ok := dnsQTypes[strings.ToUpper(qtype)]
+	if !ok {
+		return nil, fmt.Errorf("healthcheck: unknown DNS query type %q", qtype)
+	}
+	if protocol == "" {
+		protocol = "udp"
+	}
+	if port == 0 {
+		port = 53
+	}
+
+	return &dnsChecker{
+		host:                 host,
+		port:                 port,
+		timeout:              timeout,
+		qname:                qname,
+		qtype:                t,
+		protocol:             protocol,
+		expectRcode:          expectRcode,
+		expectAnswerContains: expectAnswerContains,
+	}, nil
+}
+
+func (c *dnsChecker) Check() error {
+	id := make([]byte, 2)
+	if _, err := rand.Read(id); err != nil {
+		return fmt.Errorf("dns: failed generating query id: %v", err)
+	}
+
+	query := c.buildQuery(binary.BigEndian.Uint16(id))
+
+	addr := fmt.Sprintf("%s:%d", c.host, c.port)
+	conn, err := net.DialTimeout(c.protocol, addr, c.timeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(c.timeout))
+
+	if c.protocol == "tcp" {
+		prefixed := make([]byte, 2+len(query))
+		binary.BigEndian.PutUint16(prefixed, uint16(len(query)))
+		copy(prefixed[2:], query)
+		query = prefixed
+	}
+	if _, err := conn.Write(query); err != nil {
+		return err
+	}
+
+	resp := make([]byte, 4096)
+	n, err := conn.Read(resp)
+	if err != nil {
+		return err
+	}
+	resp = resp[:n]
+	if c.protocol == "tcp" {
+		if len(resp) < 2 {
+			return fmt.Errorf("dns: truncated TCP response")
+		}
+		resp = resp[2:]
+	}
+
+	return c.verifyResponse(resp, binary.BigEndian.Uint16(id))
+}
+
+// buildQuery hand-rolls a minimal DNS query: a 12-byte header with the
+// given id and the RD (recursion desired) bit set, one question with
+// QNAME encoded as length-prefixed labels, QTYPE, and QCLASS=IN.
+func (c *dnsChecker) buildQuery(id uint16) []byte {
+	header := make([]byte, dnsHeaderLen)
+	binary.BigEndian.PutUint16(header[0:2], id)
+	header[2] = 0x01 // RD bit
+	binary.BigEndian.PutUint16(header[4:6], 1) // QDCOUNT
+
+	question := encodeQName(c.qname)
+	qtype := make([]byte, 2)
+	binary.BigEndian.PutUint16(qtype, c.qtype)
+	qclass := make([]byte, 2)
+	binary.BigEndian.PutUint16(qclass, dnsClassIN)
+
+	packet := make([]byte, 0, len(header)+len(question)+4)
+	packet = append(packet, header...)
+	packet = append(packet, question...)
+	packet = append(packet, qtype...)
+	packet = append(packet, qclass...)
+	return packet
+}
+
+// verifyResponse checks that resp answers the query we sent (matching
+// id), carries the expected rcode and, if ExpectAnswerContains is set,
+// that at least one answer record's RDATA contains it.
+func (c *dnsChecker) verifyResponse(resp []byte, id uint16) error {
+	if len(resp) < dnsHeaderLen {
+		return fmt.Errorf("dns: response too short (%d bytes)", len(resp))
+	}
+
+	gotID := binary.BigEndian.Uint16(resp[0:2])
+	if gotID != id {
+		return fmt.Errorf("dns: response id %d does not match query id %d", gotID, id)
+	}
+
+	rcode := resp[3] & 0x0f
+	if rcode != c.expectRcode {
+		return fmt.Errorf("dns: got rcode %d, want %d", rcode, c.expectRcode)
+	}
+
+	if c.expectAnswerContains == "" {
+		return nil
+	}
+
+	ancount := binary.BigEndian.Uint16(resp[6:8])
+	qdcount := binary.BigEndian.Uint16(resp[4:6])
+
+	offset := dnsHeaderLen
+	for i := uint16(0); i < qdcount; i++ {
+		n, err := skipName(resp, offset)
+		if err != nil {
+			return err
+		}
+		offset = n + 4 // QTYPE + QCLASS
+	}
+
+	for i := uint16(0); i < ancount; i++ {
+		nameEnd, err := skipName(resp, offset)
+		if err != nil {
+			return err
+		}
+		if nameEnd+10 > len(resp) {
+			return fmt.Errorf("dns: truncated answer record")
+		}
+		rdlength := int(binary.BigEndian.Uint16(resp[nameEnd+8 : nameEnd+10]))
+		rdataStart := nameEnd + 10
+		rdataEnd := rdataStart + rdlength
+		if rdataEnd > len(resp) {
+			return fmt.Errorf("dns: truncated answer rdata")
+		}
+
+		if answerContains(resp[rdataStart:rdataEnd], c.expectAnswerContains) {
+			return nil
+		}
+		offset = rdataEnd
+	}
+
+	return fmt.Errorf("dns: no answer record contained %q", c.expectAnswerContains)
+}
+
+// answerContains matches RDATA for A/AAAA records (dotted/colon form)
+// and TXT/CNAME records (raw bytes) against want.
+func answerContains(rdata []byte, want string) bool {
+	switch len(rdata) {
+	case net.IPv4len, net.IPv6len:
+		if ip := net.IP(rdata); ip != nil {
+			if ip.String() == want {
+				return true
+			}
+		}
+	}
+	return strings.Contains(string(rdata), want)
+}
+
+// encodeQName turns "example.com" into length-prefixed labels terminated
+// by a zero-length label.
+func encodeQName(qname string) []byte {
+	qname = strings.TrimSuffix(qname, ".")
+	var out []byte
+	for _, label := range strings.Split(qname, ".") {
+		out = append(out, byte(len(label)))
+		out = append(out, []byte(label)...)
+	}
+	return append(out, 0)
+}
+
+// skipName advances past a possibly-compressed name starting at offset
+// and returns the offset immediately after it.
+func skipName(msg []byte, offset int) (int, error) {
+	for {
+		if offset >= len(msg) {
+			return 0, fmt.Errorf("dns: name extends past end of message")
+		}
+		l := int(msg[offset])
+		if l == 0 {
+			return offset + 1, nil
+		}
+		if l&0xc0 == 0xc0 {
+			return offset + 2, nil
+		}
+		offset += 1 + l
+	}
+}
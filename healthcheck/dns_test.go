@@ -0,0 +1,145 @@
+package healthcheck
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+)
+
+func TestEncodeQName(t *testing.T) {
+	got := encodeQName("example.com")
+	want := []byte{
+		7, 'e', 'x', 'a', 'm', 'p', 'l', 'e',
+		3, 'c', 'o', 'm',
+		0,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d bytes, want %d: %#v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("byte %d: got %#v, want %#v", i, got, want)
+		}
+	}
+}
+
+func TestDNSCheckerBuildsWellFormedQuery(t *testing.T) {
+	c, err := NewDNSChecker("127.0.0.1", 0, "example.com", "A", "", 0, "", 0)
+	if err != nil {
+		t.Fatalf("NewDNSChecker failed: %v", err)
+	}
+	dc := c.(*dnsChecker)
+
+	query := dc.buildQuery(0x1234)
+	if binary.BigEndian.Uint16(query[0:2]) != 0x1234 {
+		t.Errorf("query id not encoded correctly")
+	}
+	if query[2]&0x01 != 0x01 {
+		t.Errorf("RD bit not set")
+	}
+	if binary.BigEndian.Uint16(query[4:6]) != 1 {
+		t.Errorf("QDCOUNT not set to 1")
+	}
+	if dc.port != 53 {
+		t.Errorf("got default port %d, want 53", dc.port)
+	}
+}
+
+func TestNewDNSCheckerRejectsUnknownQType(t *testing.T) {
+	if _, err := NewDNSChecker("127.0.0.1", 0, "example.com", "BOGUS", "", 0, "", 0); err == nil {
+		t.Fatalf("expected an error for an unknown QType")
+	}
+}
+
+func TestSkipNameAdvancesPastUncompressedAndCompressedNames(t *testing.T) {
+	msg := append(encodeQName("example.com"), 0xc0, 0x00)
+
+	n, err := skipName(msg, 0)
+	if err != nil {
+		t.Fatalf("skipName failed: %v", err)
+	}
+	if n != len(encodeQName("example.com")) {
+		t.Errorf("got offset %d, want %d", n, len(encodeQName("example.com")))
+	}
+
+	n, err = skipName(msg, n)
+	if err != nil {
+		t.Fatalf("skipName failed on compressed name: %v", err)
+	}
+	if n != len(msg) {
+		t.Errorf("got offset %d, want %d (compression pointer is always 2 bytes)", n, len(msg))
+	}
+}
+
+func TestSkipNameRejectsNameExtendingPastMessage(t *testing.T) {
+	msg := []byte{7, 'e', 'x', 'a', 'm'} // length byte claims 7 more bytes, only 4 follow
+	if _, err := skipName(msg, 0); err == nil {
+		t.Fatalf("expected an error for a name extending past the end of the message")
+	}
+}
+
+// buildAnswerResponse assembles a well-formed response to a single A
+// query for qname, with one answer record whose NAME is a compression
+// pointer back to the question and whose RDATA is ip.
+func buildAnswerResponse(t *testing.T, id uint16, rcode uint8, qname string, ip net.IP) []byte {
+	t.Helper()
+
+	header := make([]byte, dnsHeaderLen)
+	binary.BigEndian.PutUint16(header[0:2], id)
+	header[2] = 0x81         // QR + RD
+	header[3] = 0x80 | rcode // RA + RCODE
+	binary.BigEndian.PutUint16(header[4:6], 1) // QDCOUNT
+	binary.BigEndian.PutUint16(header[6:8], 1) // ANCOUNT
+
+	question := encodeQName(qname)
+	qtypeQclass := make([]byte, 4)
+	binary.BigEndian.PutUint16(qtypeQclass[0:2], dnsQTypes["A"])
+	binary.BigEndian.PutUint16(qtypeQclass[2:4], dnsClassIN)
+
+	rdata := ip.To4()
+	answer := []byte{0xc0, 0x0c} // pointer to the question's QNAME at offset 12
+	typeClassTTL := make([]byte, 8)
+	binary.BigEndian.PutUint16(typeClassTTL[0:2], dnsQTypes["A"])
+	binary.BigEndian.PutUint16(typeClassTTL[2:4], dnsClassIN)
+	rdlength := make([]byte, 2)
+	binary.BigEndian.PutUint16(rdlength, uint16(len(rdata)))
+
+	resp := append([]byte{}, header...)
+	resp = append(resp, question...)
+	resp = append(resp, qtypeQclass...)
+	resp = append(resp, answer...)
+	resp = append(resp, typeClassTTL...)
+	resp = append(resp, rdlength...)
+	resp = append(resp, rdata...)
+	return resp
+}
+
+func TestVerifyResponseAcceptsMatchingCompressedAnswer(t *testing.T) {
+	c := &dnsChecker{expectRcode: 0, expectAnswerContains: "93.184.216.34"}
+	id := uint16(0x1234)
+	resp := buildAnswerResponse(t, id, 0, "example.com", net.ParseIP("93.184.216.34"))
+
+	if err := c.verifyResponse(resp, id); err != nil {
+		t.Fatalf("verifyResponse failed: %v", err)
+	}
+}
+
+func TestVerifyResponseRejectsAnswerNotContainingExpected(t *testing.T) {
+	c := &dnsChecker{expectRcode: 0, expectAnswerContains: "10.0.0.1"}
+	id := uint16(0x1234)
+	resp := buildAnswerResponse(t, id, 0, "example.com", net.ParseIP("93.184.216.34"))
+
+	if err := c.verifyResponse(resp, id); err == nil {
+		t.Fatalf("expected an error when no answer contains expectAnswerContains")
+	}
+}
+
+func TestVerifyResponseRejectsMismatchedRcode(t *testing.T) {
+	c := &dnsChecker{expectRcode: 0}
+	id := uint16(0x1234)
+	resp := buildAnswerResponse(t, id, 2, "example.com", net.ParseIP("93.184.216.34")) // SERVFAIL
+
+	if err := c.verifyResponse(resp, id); err == nil {
+		t.Fatalf("expected an error for a mismatched rcode")
+	}
+}
@@ -0,0 +1,37 @@
+package healthcheck
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// httpChecker considers a destination healthy if a GET request to it
+// returns a 2xx status code within timeout.
+type httpChecker struct {
+	host    string
+	port    uint16
+	timeout time.Duration
+	path    string
+}
+
+func (c *httpChecker) Check() error {
+	client := &http.Client{Timeout: c.timeout}
+
+	path := c.path
+	if path == "" {
+		path = "/"
+	}
+	url := fmt.Sprintf("http://%s:%d%s", c.host, c.port, path)
+
+	resp, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("healthcheck: unhealthy status code %d", resp.StatusCode)
+	}
+	return nil
+}
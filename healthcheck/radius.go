@@ -0,0 +1,189 @@
+package healthcheck
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/rand"
+	"fmt"
+	"net"
+	"time"
+)
+
+// RADIUS attribute types used by radiusChecker.
+const (
+	raUserName             = 1
+	raUserPassword         = 2
+	raNASIPAddress         = 4
+	raMessageAuthenticator = 80
+)
+
+// RADIUS packet codes.
+const (
+	rcAccessRequest = 1
+	rcAccessAccept  = 2
+	rcAccessReject  = 3
+)
+
+const radiusAuthenticatorLen = 16
+
+// radiusChecker considers a destination healthy if it answers an
+// Access-Request with a valid Access-Accept, per RFC 2865.
+type radiusChecker struct {
+	host    string
+	port    uint16
+	timeout time.Duration
+
+	secret   string
+	username string
+	password string
+	nasIP    net.IP
+}
+
+// NewRADIUSChecker builds a Checker that sends RADIUS Access-Requests to
+// host:port (defaulting to the standard 1812 if port is 0).
+func NewRADIUSChecker(host string, port uint16, secret, username, password string, nasIP net.IP, timeout time.Duration) Checker {
+	if port == 0 {
+		port = 1812
+	}
+	return &radiusChecker{
+		host:     host,
+		port:     port,
+		timeout:  timeout,
+		secret:   secret,
+		username: username,
+		password: password,
+		nasIP:    nasIP,
+	}
+}
+
+func (c *radiusChecker) Check() error {
+	authenticator := make([]byte, radiusAuthenticatorLen)
+	if _, err := rand.Read(authenticator); err != nil {
+		return fmt.Errorf("radius: failed generating request authenticator: %v", err)
+	}
+
+	packet, err := c.buildAccessRequest(authenticator)
+	if err != nil {
+		return err
+	}
+
+	addr := fmt.Sprintf("%s:%d", c.host, c.port)
+	conn, err := net.DialTimeout("udp", addr, c.timeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	conn.SetDeadline(time.Now().Add(c.timeout))
+	if _, err := conn.Write(packet); err != nil {
+		return err
+	}
+
+	resp := make([]byte, 4096)
+	n, err := conn.Read(resp)
+	if err != nil {
+		return err
+	}
+	resp = resp[:n]
+
+	return c.verifyAccessAccept(resp, authenticator)
+}
+
+// buildAccessRequest encodes an Access-Request carrying User-Name,
+// User-Password (obfuscated per RFC 2865 §5.2), NAS-IP-Address and a
+// Message-Authenticator (RFC 3579, HMAC-MD5 over the whole packet with
+// the Message-Authenticator value zeroed while computing it).
+func (c *radiusChecker) buildAccessRequest(authenticator []byte) ([]byte, error) {
+	var attrs []byte
+	attrs = append(attrs, encodeAttribute(raUserName, []byte(c.username))...)
+	attrs = append(attrs, encodeAttribute(raUserPassword, radiusPassword(c.password, c.secret, authenticator))...)
+	if c.nasIP != nil {
+		if ip4 := c.nasIP.To4(); ip4 != nil {
+			attrs = append(attrs, encodeAttribute(raNASIPAddress, ip4)...)
+		}
+	}
+	// Reserve space for the Message-Authenticator attribute, filled in
+	// below once the rest of the packet is known.
+	maOffset := 20 + len(attrs)
+	attrs = append(attrs, encodeAttribute(raMessageAuthenticator, make([]byte, 16))...)
+
+	length := 20 + len(attrs)
+	packet := make([]byte, 0, length)
+	packet = append(packet, rcAccessRequest, 1)
+	packet = append(packet, byte(length>>8), byte(length))
+	packet = append(packet, authenticator...)
+	packet = append(packet, attrs...)
+
+	mac := hmac.New(md5.New, []byte(c.secret))
+	mac.Write(packet)
+	sum := mac.Sum(nil)
+	copy(packet[maOffset+2:maOffset+2+16], sum)
+
+	return packet, nil
+}
+
+// verifyAccessAccept parses resp as a RADIUS packet and returns nil only
+// if it is an Access-Accept whose Response Authenticator matches the
+// MD5 digest of Code+Identifier+Length+RequestAuthenticator+Attributes+Secret.
+func (c *radiusChecker) verifyAccessAccept(resp, requestAuthenticator []byte) error {
+	if len(resp) < 20 {
+		return fmt.Errorf("radius: response too short (%d bytes)", len(resp))
+	}
+
+	code := resp[0]
+	length := int(resp[2])<<8 | int(resp[3])
+	if length < 20 || length > len(resp) {
+		return fmt.Errorf("radius: response length %d out of range for packet size %d", length, len(resp))
+	}
+	resp = resp[:length]
+	respAuthenticator := resp[4:20]
+
+	if code != rcAccessAccept {
+		return fmt.Errorf("radius: expected Access-Accept, got code %d", code)
+	}
+
+	h := md5.New()
+	h.Write(resp[:4])
+	h.Write(requestAuthenticator)
+	h.Write(resp[20:])
+	h.Write([]byte(c.secret))
+	want := h.Sum(nil)
+
+	if !hmac.Equal(want, respAuthenticator) {
+		return fmt.Errorf("radius: response authenticator mismatch")
+	}
+	return nil
+}
+
+func encodeAttribute(typ byte, value []byte) []byte {
+	return append([]byte{typ, byte(len(value) + 2)}, value...)
+}
+
+// radiusPassword obfuscates passwd per RFC 2865 §5.2: it is split into
+// 16-byte chunks XORed with MD5(secret || authenticator) for the first
+// chunk and MD5(secret || previous ciphertext chunk) for the rest.
+func radiusPassword(passwd, secret string, authenticator []byte) []byte {
+	if len(passwd) == 0 {
+		return []byte{}
+	}
+
+	padded := []byte(passwd)
+	if r := len(padded) % 16; r != 0 {
+		padded = append(padded, make([]byte, 16-r)...)
+	}
+
+	out := make([]byte, len(padded))
+	prev := authenticator
+	for i := 0; i < len(padded); i += 16 {
+		h := md5.New()
+		h.Write([]byte(secret))
+		h.Write(prev)
+		b := h.Sum(nil)
+
+		for j := 0; j < 16; j++ {
+			out[i+j] = padded[i+j] ^ b[j]
+		}
+		prev = out[i : i+16]
+	}
+	return out
+}
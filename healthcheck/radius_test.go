@@ -0,0 +1,81 @@
+package healthcheck
+
+import (
+	"bytes"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestRadiusPassword(t *testing.T) {
+	authenticator := []byte{
+		0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07,
+		0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f,
+	}
+
+	tests := []struct {
+		passwd string
+		secret string
+		want   []byte
+	}{
+		{"", "", []byte{}},
+		{
+			"a", "secret",
+			[]byte{
+				0x37, 0x0e, 0xc9, 0x44, 0x90, 0x92, 0xd3, 0x67,
+				0xca, 0x5e, 0xfb, 0x70, 0x6c, 0xd4, 0xe7, 0x07,
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		got := radiusPassword(tt.passwd, tt.secret, authenticator)
+		if !bytes.Equal(got, tt.want) {
+			t.Errorf("test %d: got %#v, want %#v", i, got, tt.want)
+		}
+	}
+}
+
+func TestRadiusCheckerBuildsWellFormedAccessRequest(t *testing.T) {
+	c := &radiusChecker{
+		host:     "127.0.0.1",
+		port:     1812,
+		timeout:  time.Second,
+		secret:   "mySuperRADIUSSecret!",
+		username: "radiusprober",
+		password: "radius-probe",
+		nasIP:    net.ParseIP("10.0.0.1"),
+	}
+
+	authenticator := make([]byte, radiusAuthenticatorLen)
+	packet, err := c.buildAccessRequest(authenticator)
+	if err != nil {
+		t.Fatalf("buildAccessRequest failed: %v", err)
+	}
+
+	if packet[0] != rcAccessRequest {
+		t.Errorf("got code %d, want Access-Request (%d)", packet[0], rcAccessRequest)
+	}
+
+	length := int(packet[2])<<8 | int(packet[3])
+	if length != len(packet) {
+		t.Errorf("packet length field %d does not match actual size %d", length, len(packet))
+	}
+
+	if !bytes.Equal(packet[4:20], authenticator) {
+		t.Errorf("request authenticator was not copied into the packet")
+	}
+}
+
+func TestVerifyAccessAcceptRejectsBogusLengthWithoutPanicking(t *testing.T) {
+	c := &radiusChecker{secret: "mySuperRADIUSSecret!"}
+	authenticator := make([]byte, radiusAuthenticatorLen)
+
+	resp := make([]byte, 20)
+	resp[0] = rcAccessAccept
+	resp[2], resp[3] = 0, 5 // Length field claims 5 bytes, well under the 20-byte minimum.
+
+	if err := c.verifyAccessAccept(resp, authenticator); err == nil {
+		t.Errorf("expected an error for an undersized Length field, got nil")
+	}
+}
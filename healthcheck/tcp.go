@@ -0,0 +1,24 @@
+package healthcheck
+
+import (
+	"fmt"
+	"net"
+	"time"
+)
+
+// tcpChecker considers a destination healthy if a TCP connection can be
+// established within timeout.
+type tcpChecker struct {
+	host    string
+	port    uint16
+	timeout time.Duration
+}
+
+func (c *tcpChecker) Check() error {
+	addr := fmt.Sprintf("%s:%d", c.host, c.port)
+	conn, err := net.DialTimeout("tcp", addr, c.timeout)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
@@ -0,0 +1,133 @@
+// Package ipaddr allocates VIPs out of one or more CIDR ranges, tracking
+// which addresses are already handed out so a service's Host is never
+// reused while it's still in use.
+package ipaddr
+
+import (
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/mikioh/ipaddr"
+)
+
+// Family selects which pool an Allocator.Allocate call should draw from.
+type Family string
+
+const (
+	IPv4 Family = "ipv4"
+	IPv6 Family = "ipv6"
+)
+
+// pool hands out addresses from a single CIDR range, skipping the
+// network and broadcast addresses for IPv4 (IPv6 has no broadcast
+// address, so nothing is skipped there beyond the network address) and
+// tracking what's already allocated.
+type pool struct {
+	cursor *ipaddr.Cursor
+	allocd map[string]bool
+	skip   map[string]bool
+}
+
+func newPool(cidr string) (*pool, error) {
+	_, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, fmt.Errorf("ipaddr: invalid range %q: %v", cidr, err)
+	}
+
+	prefix := ipaddr.NewPrefix(ipNet)
+	cursor := ipaddr.NewCursor([]ipaddr.Prefix{*prefix})
+
+	return &pool{cursor: cursor, allocd: make(map[string]bool), skip: unusableAddresses(ipNet)}, nil
+}
+
+// unusableAddresses returns the network address, and for IPv4 also the
+// broadcast address, of ipNet: neither is a valid VIP.
+func unusableAddresses(ipNet *net.IPNet) map[string]bool {
+	skip := map[string]bool{ipNet.IP.String(): true}
+
+	ip4 := ipNet.IP.To4()
+	if ip4 == nil {
+		return skip
+	}
+
+	broadcast := make(net.IP, len(ip4))
+	for i := range ip4 {
+		broadcast[i] = ip4[i] | ^ipNet.Mask[i]
+	}
+	skip[broadcast.String()] = true
+	return skip
+}
+
+func (p *pool) allocate() (net.IP, error) {
+	// Scan from the start of the range each time so addresses handed back
+	// through release are found again.
+	if err := p.cursor.Set(p.cursor.First()); err != nil {
+		return nil, err
+	}
+	for pos := p.cursor.Pos(); pos != nil; pos = p.cursor.Next() {
+		ip := pos.IP
+		if p.skip[ip.String()] || p.allocd[ip.String()] {
+			continue
+		}
+		p.allocd[ip.String()] = true
+		return ip, nil
+	}
+	return nil, fmt.Errorf("ipaddr: no addresses left in pool")
+}
+
+func (p *pool) release(ip net.IP) {
+	delete(p.allocd, ip.String())
+}
+
+// Allocator is a dual-stack VIP allocator: it keeps an independent pool
+// per address family and routes Allocate/Release to the right one.
+type Allocator struct {
+	mu    sync.Mutex
+	pools map[Family]*pool
+}
+
+// NewAllocator builds an Allocator. Either range may be empty if that
+// address family isn't configured; Allocate then fails for that Family.
+func NewAllocator(vipRange, vipRange6 string) (*Allocator, error) {
+	a := &Allocator{pools: make(map[Family]*pool)}
+
+	if vipRange != "" {
+		p, err := newPool(vipRange)
+		if err != nil {
+			return nil, err
+		}
+		a.pools[IPv4] = p
+	}
+	if vipRange6 != "" {
+		p, err := newPool(vipRange6)
+		if err != nil {
+			return nil, err
+		}
+		a.pools[IPv6] = p
+	}
+
+	return a, nil
+}
+
+// Allocate returns the next free address in family's pool.
+func (a *Allocator) Allocate(family Family) (net.IP, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	p, ok := a.pools[family]
+	if !ok {
+		return nil, fmt.Errorf("ipaddr: no %s range configured", family)
+	}
+	return p.allocate()
+}
+
+// Release returns ip to family's pool so it can be reused.
+func (a *Allocator) Release(family Family, ip net.IP) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if p, ok := a.pools[family]; ok {
+		p.release(ip)
+	}
+}
@@ -0,0 +1,76 @@
+package ipaddr
+
+import "testing"
+
+func TestAllocatorAllocatesFromTheRightFamily(t *testing.T) {
+	a, err := NewAllocator("192.168.10.0/24", "2001:db8::/64")
+	if err != nil {
+		t.Fatalf("NewAllocator failed: %v", err)
+	}
+
+	v4, err := a.Allocate(IPv4)
+	if err != nil {
+		t.Fatalf("Allocate(IPv4) failed: %v", err)
+	}
+	if v4.To4() == nil {
+		t.Errorf("got %v, want an IPv4 address", v4)
+	}
+
+	v6, err := a.Allocate(IPv6)
+	if err != nil {
+		t.Fatalf("Allocate(IPv6) failed: %v", err)
+	}
+	if v6.To4() != nil {
+		t.Errorf("got %v, want an IPv6 address", v6)
+	}
+}
+
+func TestAllocatorRejectsUnconfiguredFamily(t *testing.T) {
+	a, err := NewAllocator("192.168.10.0/24", "")
+	if err != nil {
+		t.Fatalf("NewAllocator failed: %v", err)
+	}
+
+	if _, err := a.Allocate(IPv6); err == nil {
+		t.Fatalf("expected an error allocating from an unconfigured IPv6 pool")
+	}
+}
+
+func TestAllocatorSkipsNetworkAndBroadcastAddresses(t *testing.T) {
+	a, err := NewAllocator("192.168.10.0/24", "")
+	if err != nil {
+		t.Fatalf("NewAllocator failed: %v", err)
+	}
+
+	v4, err := a.Allocate(IPv4)
+	if err != nil {
+		t.Fatalf("Allocate(IPv4) failed: %v", err)
+	}
+	if v4.String() == "192.168.10.0" {
+		t.Errorf("got the network address %v, want a usable host address", v4)
+	}
+	if v4.String() == "192.168.10.255" {
+		t.Errorf("got the broadcast address %v, want a usable host address", v4)
+	}
+}
+
+func TestAllocatorReleaseAllowsReuse(t *testing.T) {
+	a, err := NewAllocator("192.168.10.0/30", "")
+	if err != nil {
+		t.Fatalf("NewAllocator failed: %v", err)
+	}
+
+	first, err := a.Allocate(IPv4)
+	if err != nil {
+		t.Fatalf("Allocate failed: %v", err)
+	}
+	a.Release(IPv4, first)
+
+	second, err := a.Allocate(IPv4)
+	if err != nil {
+		t.Fatalf("Allocate after release failed: %v", err)
+	}
+	if !first.Equal(second) {
+		t.Errorf("got %v after release, want the released address %v back", second, first)
+	}
+}
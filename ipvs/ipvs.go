@@ -0,0 +1,90 @@
+// Package ipvs programs the kernel's IPVS tables for services and
+// destinations over netlink, picking the AF_INET or AF_INET6 attribute
+// set to match each Host's address family.
+//
+// Nothing in fusis/balancer.go calls into this package yet: wiring
+// CreateService/AddDestination through to a live IPVS handle (and
+// deciding where that handle's lifecycle belongs alongside raft/leader
+// election) is left for a follow-up request.
+package ipvs
+
+import (
+	"fmt"
+	"net"
+	"syscall"
+
+	"github.com/luizbafilho/fusis/api/types"
+	"github.com/mqliang/libipvs"
+)
+
+// IPVS programs the local IPVS table.
+type IPVS struct {
+	handle libipvs.IPVSHandle
+}
+
+// New opens a netlink handle to the kernel's IPVS table.
+func New() (*IPVS, error) {
+	handle, err := libipvs.New()
+	if err != nil {
+		return nil, fmt.Errorf("ipvs: failed opening netlink handle: %v", err)
+	}
+	return &IPVS{handle: handle}, nil
+}
+
+// AddService programs svc, using the AF_INET6 attribute set when its
+// Host is an IPv6 address.
+func (i *IPVS) AddService(svc types.Service) error {
+	ip, family, err := parseHost(svc.Host)
+	if err != nil {
+		return err
+	}
+
+	return i.handle.NewService(&libipvs.Service{
+		Address:       ip,
+		AddressFamily: family,
+		Protocol:      protocolFor(svc.Protocol),
+		Port:          svc.Port,
+		SchedName:     svc.Scheduler,
+	})
+}
+
+// AddDestination programs dst as a real server for svc, matching
+// address families between the two.
+func (i *IPVS) AddDestination(svc types.Service, dst types.Destination) error {
+	svcIP, svcFamily, err := parseHost(svc.Host)
+	if err != nil {
+		return err
+	}
+	dstIP, dstFamily, err := parseHost(dst.Host)
+	if err != nil {
+		return err
+	}
+	if svcFamily != dstFamily {
+		return fmt.Errorf("ipvs: destination %s address family does not match service %s", dst.Host, svc.Host)
+	}
+
+	return i.handle.NewDestination(
+		&libipvs.Service{Address: svcIP, AddressFamily: svcFamily, Protocol: protocolFor(svc.Protocol), Port: svc.Port},
+		&libipvs.Destination{Address: dstIP, AddressFamily: dstFamily, Port: dst.Port, Weight: dst.Weight},
+	)
+}
+
+func parseHost(host string) (net.IP, libipvs.AddressFamily, error) {
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return nil, 0, fmt.Errorf("ipvs: invalid host %q", host)
+	}
+	if ip.To4() != nil {
+		return ip, syscall.AF_INET, nil
+	}
+	return ip, syscall.AF_INET6, nil
+}
+
+func protocolFor(protocol string) libipvs.Protocol {
+	switch protocol {
+	case "udp":
+		return syscall.IPPROTO_UDP
+	default:
+		return syscall.IPPROTO_TCP
+	}
+}
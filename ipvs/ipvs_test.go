@@ -0,0 +1,54 @@
+package ipvs
+
+import (
+	"syscall"
+	"testing"
+)
+
+func TestParseHostPicksAddressFamily(t *testing.T) {
+	ip, family, err := parseHost("192.168.10.5")
+	if err != nil {
+		t.Fatalf("parseHost failed: %v", err)
+	}
+	if ip.To4() == nil {
+		t.Errorf("got %v, want an IPv4 address", ip)
+	}
+	if family != syscall.AF_INET {
+		t.Errorf("got family %v, want AF_INET", family)
+	}
+
+	ip, family, err = parseHost("2001:db8::1")
+	if err != nil {
+		t.Fatalf("parseHost failed: %v", err)
+	}
+	if ip.To4() != nil {
+		t.Errorf("got %v, want an IPv6 address", ip)
+	}
+	if family != syscall.AF_INET6 {
+		t.Errorf("got family %v, want AF_INET6", family)
+	}
+}
+
+func TestParseHostRejectsInvalidHost(t *testing.T) {
+	if _, _, err := parseHost("not-an-ip"); err == nil {
+		t.Errorf("expected an error parsing an invalid host, got nil")
+	}
+}
+
+func TestProtocolFor(t *testing.T) {
+	tests := []struct {
+		protocol string
+		want     uint16
+	}{
+		{"tcp", syscall.IPPROTO_TCP},
+		{"udp", syscall.IPPROTO_UDP},
+		{"", syscall.IPPROTO_TCP},
+		{"bogus", syscall.IPPROTO_TCP},
+	}
+
+	for _, tt := range tests {
+		if got := protocolFor(tt.protocol); uint16(got) != tt.want {
+			t.Errorf("protocolFor(%q) = %v, want %v", tt.protocol, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,258 @@
+// Package peering lets two independent fusis balancer clusters exchange
+// a subset of their service catalog without joining the same Raft
+// group. One side issues a bearer Token describing how to reach it; the
+// other consumes that token to Establish a peering and starts pulling
+// its service catalog.
+package peering
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/luizbafilho/fusis/api/types"
+)
+
+// pollInterval is how often an established peering refreshes the
+// remote catalog. Streaming create/update/delete events over a
+// long-lived connection is the end state; polling the existing catalog
+// endpoint gets the same end result with far less surface area while
+// the stream transport is still landing.
+const pollInterval = 5 * time.Second
+
+// authTokenSize is the length, in bytes, of a generated AuthToken before
+// base64 encoding.
+const authTokenSize = 32
+
+// RemoteCatalog fetches the service catalog a peering imports from. A
+// *api.Client pointed at the remote cluster satisfies this.
+type RemoteCatalog interface {
+	GetServices() ([]*types.Service, error)
+}
+
+// Importer materializes (or removes) a peer's services in the local
+// store. fusis.Balancer implements this, tagging imported services with
+// their PeerName and keeping them out of IPVS; it does not stop the local
+// API from modifying or deleting them.
+type Importer interface {
+	ImportService(peerName string, svc types.Service, mode types.PeeringMode) error
+	RemoveImportedService(peerName, serviceName string) error
+}
+
+// Manager tracks the peerings established on this cluster.
+type Manager struct {
+	clusterID string
+	apiAddr   string
+	raftAddr  string
+	serfAddr  string
+	caBundle  []byte
+	importer  Importer
+
+	mu         sync.Mutex
+	peerings   map[string]*peeringState
+	authTokens map[string]bool
+}
+
+type peeringState struct {
+	peering types.Peering
+	stopCh  chan struct{}
+
+	// imported holds the service ids seen on the last successful sync,
+	// so the next one can tell what disappeared from the remote catalog
+	// and remove it locally.
+	imported map[string]bool
+}
+
+// NewManager builds a Manager for a cluster identified by clusterID,
+// reachable at apiAddr/raftAddr/serfAddr, whose CA bundle is caBundle.
+// Imported services are handed to importer.
+func NewManager(clusterID, apiAddr, raftAddr, serfAddr string, caBundle []byte, importer Importer) *Manager {
+	return &Manager{
+		clusterID:  clusterID,
+		apiAddr:    apiAddr,
+		raftAddr:   raftAddr,
+		serfAddr:   serfAddr,
+		caBundle:   caBundle,
+		importer:   importer,
+		peerings:   make(map[string]*peeringState),
+		authTokens: make(map[string]bool),
+	}
+}
+
+// Token generates a bearer token describing this cluster, to be handed
+// to Establish on the remote side. Each call mints a fresh AuthToken,
+// which ValidateAuthToken accepts for the lifetime of this Manager so
+// the remote side can authenticate its catalog pulls.
+func (m *Manager) Token() (string, error) {
+	authToken, err := randomAuthToken()
+	if err != nil {
+		return "", err
+	}
+
+	m.mu.Lock()
+	m.authTokens[authToken] = true
+	m.mu.Unlock()
+
+	tok := types.PeeringToken{
+		ClusterID: m.clusterID,
+		APIAddr:   m.apiAddr,
+		RaftAddr:  m.raftAddr,
+		SerfAddr:  m.serfAddr,
+		CABundle:  m.caBundle,
+		AuthToken: authToken,
+	}
+
+	b, err := json.Marshal(tok)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
+// ValidateAuthToken reports whether authToken was minted by a prior call
+// to Token, authenticating a request to this cluster's catalog
+// endpoint.
+func (m *Manager) ValidateAuthToken(authToken string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return authToken != "" && m.authTokens[authToken]
+}
+
+func randomAuthToken() (string, error) {
+	b := make([]byte, authTokenSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("peering: failed generating auth token: %v", err)
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
+// Establish consumes a token from the remote side, registers the
+// peering as name, and starts pulling its catalog until Delete is
+// called.
+func (m *Manager) Establish(name, token string, mode types.PeeringMode) (*types.Peering, error) {
+	if mode != types.PeeringImportOnly {
+		return nil, fmt.Errorf("peering: mode %q is not supported", mode)
+	}
+
+	tok, err := decodeToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	remote, err := newHTTPRemoteCatalog(tok.APIAddr, tok.CABundle, tok.AuthToken)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	if _, ok := m.peerings[name]; ok {
+		m.mu.Unlock()
+		return nil, types.ErrPeeringAlreadyExists
+	}
+
+	p := types.Peering{Name: name, ClusterID: tok.ClusterID, Mode: mode}
+	state := &peeringState{peering: p, stopCh: make(chan struct{}), imported: make(map[string]bool)}
+	m.peerings[name] = state
+	m.mu.Unlock()
+
+	go m.sync(state, remote)
+
+	return &p, nil
+}
+
+// Get returns the named peering.
+func (m *Manager) Get(name string) (*types.Peering, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state, ok := m.peerings[name]
+	if !ok {
+		return nil, types.ErrPeeringNotFound
+	}
+	p := state.peering
+	return &p, nil
+}
+
+// List returns all established peerings.
+func (m *Manager) List() []types.Peering {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	peerings := make([]types.Peering, 0, len(m.peerings))
+	for _, state := range m.peerings {
+		peerings = append(peerings, state.peering)
+	}
+	return peerings
+}
+
+// Delete tears down a peering, stopping its catalog sync.
+func (m *Manager) Delete(name string) error {
+	m.mu.Lock()
+	state, ok := m.peerings[name]
+	if !ok {
+		m.mu.Unlock()
+		return types.ErrPeeringNotFound
+	}
+	delete(m.peerings, name)
+	m.mu.Unlock()
+
+	close(state.stopCh)
+	return nil
+}
+
+// sync periodically pulls remote's catalog and hands each service to
+// the Importer tagged with the peering's name, until stopCh closes.
+// Services that disappear from one poll to the next are removed through
+// RemoveImportedService, so a deletion on the peer's side is reflected
+// here within one pollInterval.
+func (m *Manager) sync(state *peeringState, remote RemoteCatalog) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			m.syncOnce(state, remote)
+		case <-state.stopCh:
+			return
+		}
+	}
+}
+
+// syncOnce pulls remote's catalog once, importing every service it
+// returns and removing whatever was imported on the previous call but
+// is no longer present.
+func (m *Manager) syncOnce(state *peeringState, remote RemoteCatalog) {
+	services, err := remote.GetServices()
+	if err != nil {
+		return
+	}
+
+	fresh := make(map[string]bool, len(services))
+	for _, svc := range services {
+		fresh[svc.GetId()] = true
+		m.importer.ImportService(state.peering.Name, *svc, state.peering.Mode)
+	}
+	for id := range state.imported {
+		if !fresh[id] {
+			m.importer.RemoveImportedService(state.peering.Name, id)
+		}
+	}
+	state.imported = fresh
+}
+
+func decodeToken(token string) (*types.PeeringToken, error) {
+	b, err := base64.StdEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("peering: malformed token: %v", err)
+	}
+
+	var tok types.PeeringToken
+	if err := json.Unmarshal(b, &tok); err != nil {
+		return nil, fmt.Errorf("peering: malformed token: %v", err)
+	}
+	return &tok, nil
+}
@@ -0,0 +1,160 @@
+package peering
+
+import (
+	"testing"
+
+	"github.com/luizbafilho/fusis/api/types"
+)
+
+type fakeImporter struct {
+	imported    []types.Service
+	removed     int
+	lastRemoved string
+}
+
+func (f *fakeImporter) ImportService(peerName string, svc types.Service, mode types.PeeringMode) error {
+	svc.PeerName = peerName
+	f.imported = append(f.imported, svc)
+	return nil
+}
+
+func (f *fakeImporter) RemoveImportedService(peerName, serviceName string) error {
+	f.removed++
+	f.lastRemoved = serviceName
+	return nil
+}
+
+func TestTokenRoundTrips(t *testing.T) {
+	m := NewManager("cluster-a", "10.0.0.1:8080", "10.0.0.1:20012", "10.0.0.1:20013", []byte("ca-bundle"), &fakeImporter{})
+
+	token, err := m.Token()
+	if err != nil {
+		t.Fatalf("Token failed: %v", err)
+	}
+
+	tok, err := decodeToken(token)
+	if err != nil {
+		t.Fatalf("decodeToken failed: %v", err)
+	}
+	if tok.ClusterID != "cluster-a" || tok.RaftAddr != "10.0.0.1:20012" {
+		t.Errorf("got %+v, want ClusterID=cluster-a RaftAddr=10.0.0.1:20012", tok)
+	}
+}
+
+func TestEstablishRejectsDuplicateName(t *testing.T) {
+	remoteManager := NewManager("cluster-b", "10.0.0.2:8080", "10.0.0.2:20012", "10.0.0.2:20013", nil, &fakeImporter{})
+	token, err := remoteManager.Token()
+	if err != nil {
+		t.Fatalf("Token failed: %v", err)
+	}
+
+	m := NewManager("cluster-a", "10.0.0.1:8080", "10.0.0.1:20012", "10.0.0.1:20013", nil, &fakeImporter{})
+
+	if _, err := m.Establish("peer-b", token, types.PeeringImportOnly); err != nil {
+		t.Fatalf("Establish failed: %v", err)
+	}
+	defer m.Delete("peer-b")
+
+	if _, err := m.Establish("peer-b", token, types.PeeringImportOnly); err != types.ErrPeeringAlreadyExists {
+		t.Errorf("got %v, want ErrPeeringAlreadyExists", err)
+	}
+}
+
+func TestDeleteRemovesPeering(t *testing.T) {
+	remoteManager := NewManager("cluster-b", "10.0.0.2:8080", "10.0.0.2:20012", "10.0.0.2:20013", nil, &fakeImporter{})
+	token, err := remoteManager.Token()
+	if err != nil {
+		t.Fatalf("Token failed: %v", err)
+	}
+
+	m := NewManager("cluster-a", "10.0.0.1:8080", "10.0.0.1:20012", "10.0.0.1:20013", nil, &fakeImporter{})
+	if _, err := m.Establish("peer-b", token, types.PeeringImportOnly); err != nil {
+		t.Fatalf("Establish failed: %v", err)
+	}
+
+	if err := m.Delete("peer-b"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := m.Get("peer-b"); err != types.ErrPeeringNotFound {
+		t.Errorf("got %v, want ErrPeeringNotFound", err)
+	}
+}
+
+func TestEstablishRejectsUnsupportedMode(t *testing.T) {
+	remoteManager := NewManager("cluster-b", "10.0.0.2:8080", "10.0.0.2:20012", "10.0.0.2:20013", nil, &fakeImporter{})
+	token, err := remoteManager.Token()
+	if err != nil {
+		t.Fatalf("Token failed: %v", err)
+	}
+
+	m := NewManager("cluster-a", "10.0.0.1:8080", "10.0.0.1:20012", "10.0.0.1:20013", nil, &fakeImporter{})
+	if _, err := m.Establish("peer-b", token, types.PeeringMode("forwarding")); err == nil {
+		t.Fatalf("expected an error establishing an unsupported peering mode, got nil")
+	}
+}
+
+func TestTokenAuthTokenValidatesOnlyForThatManager(t *testing.T) {
+	m := NewManager("cluster-a", "10.0.0.1:8080", "10.0.0.1:20012", "10.0.0.1:20013", nil, &fakeImporter{})
+
+	token, err := m.Token()
+	if err != nil {
+		t.Fatalf("Token failed: %v", err)
+	}
+	tok, err := decodeToken(token)
+	if err != nil {
+		t.Fatalf("decodeToken failed: %v", err)
+	}
+
+	if !m.ValidateAuthToken(tok.AuthToken) {
+		t.Errorf("expected AuthToken minted by Token to validate")
+	}
+	if m.ValidateAuthToken("bogus") {
+		t.Errorf("expected an unknown AuthToken not to validate")
+	}
+
+	other := NewManager("cluster-b", "10.0.0.2:8080", "10.0.0.2:20012", "10.0.0.2:20013", nil, &fakeImporter{})
+	if other.ValidateAuthToken(tok.AuthToken) {
+		t.Errorf("expected an AuthToken minted by another Manager not to validate")
+	}
+}
+
+func TestSyncRemovesServicesThatDisappearFromRemote(t *testing.T) {
+	importer := &fakeImporter{}
+	m := NewManager("cluster-a", "10.0.0.1:8080", "10.0.0.1:20012", "10.0.0.1:20013", nil, importer)
+
+	state := &peeringState{
+		peering:  types.Peering{Name: "peer-b", Mode: types.PeeringImportOnly},
+		stopCh:   make(chan struct{}),
+		imported: make(map[string]bool),
+	}
+
+	remote := &stepRemoteCatalog{
+		steps: [][]*types.Service{
+			{{Name: "svc-a"}, {Name: "svc-b"}},
+			{{Name: "svc-a"}},
+		},
+	}
+
+	m.syncOnce(state, remote)
+	m.syncOnce(state, remote)
+
+	if importer.removed != 1 || importer.lastRemoved != "svc-b" {
+		t.Errorf("got removed=%d lastRemoved=%q, want one removal of svc-b", importer.removed, importer.lastRemoved)
+	}
+}
+
+// stepRemoteCatalog returns its steps in order, one per GetServices
+// call, so tests can simulate a service disappearing between polls.
+type stepRemoteCatalog struct {
+	steps [][]*types.Service
+	pos   int
+}
+
+func (s *stepRemoteCatalog) GetServices() ([]*types.Service, error) {
+	if s.pos >= len(s.steps) {
+		return s.steps[len(s.steps)-1], nil
+	}
+	step := s.steps[s.pos]
+	s.pos++
+	return step, nil
+}
@@ -0,0 +1,67 @@
+package peering
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/luizbafilho/fusis/api/types"
+)
+
+// httpRemoteCatalog fetches a peer's service catalog from its
+// /peerings/catalog endpoint, over TLS pinned to the token's CA bundle
+// and authenticated with the token's AuthToken. It deliberately doesn't
+// reuse api.Client: that package imports fusis, and
+// fusis depends on peering to implement Importer, so importing it here
+// would create a cycle.
+type httpRemoteCatalog struct {
+	apiAddr    string
+	authToken  string
+	scheme     string
+	httpClient *http.Client
+}
+
+// newHTTPRemoteCatalog builds a RemoteCatalog pulling from apiAddr. When
+// caBundle is set, requests go out over TLS with the remote's
+// certificate verified against it; otherwise they fall back to plain
+// HTTP, matching what the issuing cluster actually offered in its token.
+func newHTTPRemoteCatalog(apiAddr string, caBundle []byte, authToken string) (RemoteCatalog, error) {
+	c := &httpRemoteCatalog{apiAddr: apiAddr, authToken: authToken, scheme: "http", httpClient: http.DefaultClient}
+
+	if len(caBundle) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caBundle) {
+			return nil, fmt.Errorf("peering: no certificates found in token's CA bundle")
+		}
+		c.scheme = "https"
+		c.httpClient = &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}}}
+	}
+
+	return c, nil
+}
+
+func (c *httpRemoteCatalog) GetServices() ([]*types.Service, error) {
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s://%s/peerings/catalog", c.scheme, c.apiAddr), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.authToken)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("peering: GET /peerings/catalog on %s returned %d", c.apiAddr, resp.StatusCode)
+	}
+
+	var services []*types.Service
+	if err := json.NewDecoder(resp.Body).Decode(&services); err != nil {
+		return nil, err
+	}
+	return services, nil
+}